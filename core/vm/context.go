@@ -0,0 +1,61 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math/big"
+
+	"github.com/shiftcurrency/shift/common"
+)
+
+// CanTransferFunc is the signature of a transfer guard function. It reports
+// whether the account at the given address holds at least `amount`.
+type CanTransferFunc func(db Database, addr common.Address, amount *big.Int) bool
+
+// TransferFunc is the signature of a value transfer function.
+type TransferFunc func(from, to Account, amount *big.Int)
+
+// GetHashFunc returns the n'th block hash in the current chain and is used
+// by the BLOCKHASH EVM op code.
+type GetHashFunc func(n uint64) common.Hash
+
+// Context provides the EVM with auxiliary information. Once provided it
+// shouldn't be modified.
+//
+// Context replaces the old Environment interface: every embedder of the EVM
+// used to have to hand-implement more than a dozen trivial accessor methods
+// just to satisfy that interface. A plain struct can instead be filled in
+// and handed to NewEVM directly.
+type Context struct {
+	// CanTransfer returns whether the account contains
+	// sufficient ether to transfer the value
+	CanTransfer CanTransferFunc
+	// Transfer transfers ether from one account to the other
+	Transfer TransferFunc
+	// GetHash returns the hash corresponding to n
+	GetHash GetHashFunc
+
+	// Message information
+	Origin common.Address // Provides information for ORIGIN
+
+	// Block information
+	Coinbase    common.Address // Provides information for COINBASE
+	BlockNumber *big.Int       // Provides information for NUMBER
+	Time        *big.Int       // Provides information for TIME
+	Difficulty  *big.Int       // Provides information for DIFFICULTY
+	NrgLimit    uint64         // Provides information for NRGLIMIT
+}
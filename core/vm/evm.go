@@ -0,0 +1,195 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/crypto"
+	"github.com/shiftcurrency/shift/params"
+)
+
+// EVM is the Ethereum Virtual Machine base object and allows running
+// contract code against the given Context and Database. It replaces the old
+// Environment interface: embedders fill in a Context and a Database and get
+// a ready-to-use EVM, instead of having to hand-implement the interface
+// themselves.
+//
+// The EVM is not thread safe and should only ever be used by a single
+// goroutine.
+type EVM struct {
+	Context
+
+	// StateDB gives access to the underlying state
+	StateDB Database
+
+	depth int
+	logs  []StructLog
+
+	logStack, logMemory, logStorage bool
+}
+
+// NewEVM returns a new EVM. The returned EVM is not thread safe and should
+// only ever be used *once*.
+func NewEVM(ctx Context, db Database) *EVM {
+	return &EVM{
+		Context:    ctx,
+		StateDB:    db,
+		logStack:   true,
+		logMemory:  true,
+		logStorage: true,
+	}
+}
+
+func (evm *EVM) Db() Database   { return evm.StateDB }
+func (evm *EVM) Depth() int     { return evm.depth }
+func (evm *EVM) SetDepth(i int) { evm.depth = i }
+func (evm *EVM) VmType() Type   { return StdVmTy }
+func (evm *EVM) StructLogs() []StructLog {
+	return evm.logs
+}
+func (evm *EVM) AddStructLog(log StructLog) {
+	if !evm.logStack {
+		log.Stack = nil
+	}
+	if !evm.logMemory {
+		log.Memory = nil
+	}
+	if !evm.logStorage {
+		log.Storage = nil
+	}
+	evm.logs = append(evm.logs, log)
+}
+
+// SetOptions controls which fields AddStructLog records on every step,
+// letting callers (e.g. debug_traceTransaction) keep large traces bounded.
+func (evm *EVM) SetOptions(logStack, logMemory, logStorage bool) {
+	evm.logStack = logStack
+	evm.logMemory = logMemory
+	evm.logStorage = logStorage
+}
+
+func (evm *EVM) MakeSnapshot() Database {
+	return evm.StateDB.Copy()
+}
+
+func (evm *EVM) SetSnapshot(snapshot Database) {
+	evm.StateDB.Set(snapshot)
+}
+
+// Call executes the contract associated with the addr with the given input
+// as parameters. It also handles any necessary value transfer required and
+// takes the necessary steps to create accounts and reverses the state in
+// case of an execution error or failed value transfer. nrgLeft is the nrg
+// remaining after execution, for the caller to refund or account for.
+func (evm *EVM) Call(caller ContractRef, addr common.Address, input []byte, nrg uint64, price, value *big.Int) (ret []byte, nrgLeft uint64, err error) {
+	ret, _, nrgLeft, err = evm.exec(caller, &addr, &addr, input, evm.StateDB.GetCode(addr), nrg, price, value)
+	return ret, nrgLeft, err
+}
+
+// CallCode executes the given address' code as the given contract address
+func (evm *EVM) CallCode(caller ContractRef, addr common.Address, input []byte, nrg uint64, price, value *big.Int) (ret []byte, nrgLeft uint64, err error) {
+	callerAddr := caller.Address()
+	ret, _, nrgLeft, err = evm.exec(caller, &callerAddr, &addr, input, evm.StateDB.GetCode(addr), nrg, price, value)
+	return ret, nrgLeft, err
+}
+
+// DelegateCall is equivalent to CallCode except that sender and value
+// propagate from parent scope to child scope.
+func (evm *EVM) DelegateCall(caller ContractRef, addr common.Address, input []byte, nrg uint64, price *big.Int) (ret []byte, nrgLeft uint64, err error) {
+	callerAddr := caller.Address()
+	ret, _, nrgLeft, err = evm.execDelegateCall(caller, &callerAddr, &addr, input, evm.StateDB.GetCode(addr), nrg, price)
+	return ret, nrgLeft, err
+}
+
+// Create creates a new contract with the given code
+func (evm *EVM) Create(caller ContractRef, code []byte, nrg uint64, price, value *big.Int) (ret []byte, address common.Address, nrgLeft uint64, err error) {
+	ret, address, nrgLeft, err = evm.exec(caller, nil, nil, code, code, nrg, price, value)
+	return ret, address, nrgLeft, err
+}
+
+// exec executes the call denoted by the given address. The caller's balance
+// is checked up front, before any state is snapshotted, so calls that are
+// bound to fail on insufficient balance never pay for a snapshot/rollback.
+func (evm *EVM) exec(caller ContractRef, address, codeAddr *common.Address, input, code []byte, nrg uint64, price, value *big.Int) (ret []byte, addr common.Address, nrgLeft uint64, err error) {
+	if evm.depth > int(params.CallCreateDepth.Int64()) {
+		caller.ReturnGas(nrg, price)
+		return nil, common.Address{}, nrg, fmt.Errorf("max call depth exceeded")
+	}
+
+	if !evm.CanTransfer(evm.StateDB, caller.Address(), value) {
+		caller.ReturnGas(nrg, price)
+		return nil, common.Address{}, nrg, fmt.Errorf("insufficient balance for transfer")
+	}
+
+	var createAccount bool
+	if address == nil {
+		nonce := evm.StateDB.GetNonce(caller.Address())
+		evm.StateDB.SetNonce(caller.Address(), nonce+1)
+		addr = crypto.CreateAddress(caller.Address(), nonce)
+		createAccount = true
+	} else {
+		addr = *address
+	}
+
+	snapshot := evm.MakeSnapshot()
+
+	var (
+		from = evm.StateDB.GetAccount(caller.Address())
+		to   Account
+	)
+	if createAccount {
+		to = evm.StateDB.CreateAccount(addr)
+	} else if to = evm.StateDB.GetAccount(addr); to == nil {
+		to = evm.StateDB.CreateAccount(addr)
+	}
+	evm.Transfer(from, to, value)
+
+	contract := NewContract(caller, to, value, nrg, price)
+	contract.SetCallCode(codeAddr, crypto.Keccak256Hash(code), code)
+	defer contract.Finalise()
+
+	ret, err = Run(evm, contract, input)
+	if err != nil {
+		evm.SetSnapshot(snapshot)
+	}
+	return ret, addr, contract.Gas, err
+}
+
+// execDelegateCall is the same as exec but it doesn't transfer any value and
+// the called contract executes in the caller's context.
+func (evm *EVM) execDelegateCall(caller ContractRef, callerAddr, codeAddr *common.Address, input, code []byte, nrg uint64, price *big.Int) (ret []byte, addr common.Address, nrgLeft uint64, err error) {
+	if evm.depth > int(params.CallCreateDepth.Int64()) {
+		caller.ReturnGas(nrg, price)
+		return nil, common.Address{}, nrg, fmt.Errorf("max call depth exceeded")
+	}
+
+	snapshot := evm.MakeSnapshot()
+
+	to := evm.StateDB.GetAccount(*callerAddr)
+	contract := NewContract(caller, to, caller.Value(), nrg, price).AsDelegate()
+	contract.SetCallCode(codeAddr, crypto.Keccak256Hash(code), code)
+	defer contract.Finalise()
+
+	ret, err = Run(evm, contract, input)
+	if err != nil {
+		evm.SetSnapshot(snapshot)
+	}
+	return ret, *callerAddr, contract.Gas, err
+}
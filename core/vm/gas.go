@@ -0,0 +1,45 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// ToNrg converts a *big.Int gas/nrg value, as carried on a transaction, into
+// the uint64 representation used throughout the interpreter. EIP-150 already
+// caps block gas well below MaxUint64, so any value that doesn't fit is
+// rejected rather than silently truncated.
+func ToNrg(nrg *big.Int) (uint64, error) {
+	if nrg == nil {
+		return 0, nil
+	}
+	if nrg.Sign() < 0 {
+		return 0, fmt.Errorf("nrg value %v must not be negative", nrg)
+	}
+	if !nrg.IsUint64() {
+		return 0, fmt.Errorf("nrg value %v overflows uint64", nrg)
+	}
+	return nrg.Uint64(), nil
+}
+
+// FromNrg converts a uint64 nrg value back to *big.Int, e.g. for RPC
+// responses or RLP fields that still use *big.Int.
+func FromNrg(nrg uint64) *big.Int {
+	return new(big.Int).SetUint64(nrg)
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package vm
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestToNrg(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      *big.Int
+		want    uint64
+		wantErr bool
+	}{
+		{"nil", nil, 0, false},
+		{"zero", big.NewInt(0), 0, false},
+		{"typical", big.NewInt(21000), 21000, false},
+		{"maxUint64", new(big.Int).SetUint64(math.MaxUint64), math.MaxUint64, false},
+		{"negative", big.NewInt(-1), 0, true},
+		{"overflow", new(big.Int).Lsh(big.NewInt(1), 64), 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := ToNrg(test.in)
+		if test.wantErr {
+			if err == nil {
+				t.Errorf("%s: expected an error, got nil", test.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %v", test.name, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%s: got %d, want %d", test.name, got, test.want)
+		}
+	}
+}
+
+func TestFromNrg(t *testing.T) {
+	tests := []struct {
+		in   uint64
+		want *big.Int
+	}{
+		{0, big.NewInt(0)},
+		{21000, big.NewInt(21000)},
+		{math.MaxUint64, new(big.Int).SetUint64(math.MaxUint64)},
+	}
+
+	for _, test := range tests {
+		if got := FromNrg(test.in); got.Cmp(test.want) != 0 {
+			t.Errorf("FromNrg(%d) = %v, want %v", test.in, got, test.want)
+		}
+	}
+}
+
+func TestToNrgFromNrgRoundTrip(t *testing.T) {
+	for _, nrg := range []uint64{0, 1, 21000, math.MaxUint64} {
+		got, err := ToNrg(FromNrg(nrg))
+		if err != nil {
+			t.Fatalf("ToNrg(FromNrg(%d)) returned error: %v", nrg, err)
+		}
+		if got != nrg {
+			t.Errorf("ToNrg(FromNrg(%d)) = %d", nrg, got)
+		}
+	}
+}
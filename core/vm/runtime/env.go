@@ -25,86 +25,30 @@ import (
 	"github.com/shiftcurrency/shift/core/vm"
 )
 
-// Env is a basic runtime environment required for running the EVM.
-type Env struct {
-	depth int
-	state *state.StateDB
-
-	origin   common.Address
-	shiftbase common.Address
-
-	number     *big.Int
-	time       *big.Int
-	difficulty *big.Int
-	nrgLimit   *big.Int
-
-	logs []vm.StructLog
-
-	getHashFn func(uint64) common.Hash
-}
-
-// NewEnv returns a new vm.Environment
-func NewEnv(cfg *Config, state *state.StateDB) vm.Environment {
-	return &Env{
-		state:      state,
-		origin:     cfg.Origin,
-		shiftbase:   cfg.Coinbase,
-		number:     cfg.BlockNumber,
-		time:       cfg.Time,
-		difficulty: cfg.Difficulty,
-		nrgLimit:   cfg.NrgLimit,
+// NewEnv fills a vm.Context from the given Config and returns a ready to use
+// *vm.EVM, saving callers from having to hand-implement the old
+// vm.Environment interface.
+func NewEnv(cfg *Config, state *state.StateDB) *vm.EVM {
+	context := vm.Context{
+		Origin:      cfg.Origin,
+		Coinbase:    cfg.Coinbase,
+		BlockNumber: cfg.BlockNumber,
+		Time:        cfg.Time,
+		Difficulty:  cfg.Difficulty,
+		NrgLimit:    cfg.NrgLimit,
+		GetHash:     cfg.GetHashFn,
+		CanTransfer: CanTransfer,
+		Transfer:    Transfer,
 	}
+	return vm.NewEVM(context, state)
 }
 
-func (self *Env) StructLogs() []vm.StructLog {
-	return self.logs
+// CanTransfer reports whether the account at addr holds at least amount.
+func CanTransfer(db vm.Database, addr common.Address, amount *big.Int) bool {
+	return db.GetBalance(addr).Cmp(amount) >= 0
 }
 
-func (self *Env) AddStructLog(log vm.StructLog) {
-	self.logs = append(self.logs, log)
-}
-
-func (self *Env) Origin() common.Address   { return self.origin }
-func (self *Env) BlockNumber() *big.Int    { return self.number }
-func (self *Env) Coinbase() common.Address { return self.shiftbase }
-func (self *Env) Time() *big.Int           { return self.time }
-func (self *Env) Difficulty() *big.Int     { return self.difficulty }
-func (self *Env) Db() vm.Database          { return self.state }
-func (self *Env) NrgLimit() *big.Int       { return self.nrgLimit }
-func (self *Env) VmType() vm.Type          { return vm.StdVmTy }
-func (self *Env) GetHash(n uint64) common.Hash {
-	return self.getHashFn(n)
-}
-func (self *Env) AddLog(log *vm.Log) {
-	self.state.AddLog(log)
-}
-func (self *Env) Depth() int     { return self.depth }
-func (self *Env) SetDepth(i int) { self.depth = i }
-func (self *Env) CanTransfer(from common.Address, balance *big.Int) bool {
-	return self.state.GetBalance(from).Cmp(balance) >= 0
-}
-func (self *Env) MakeSnapshot() vm.Database {
-	return self.state.Copy()
-}
-func (self *Env) SetSnapshot(copy vm.Database) {
-	self.state.Set(copy.(*state.StateDB))
-}
-
-func (self *Env) Transfer(from, to vm.Account, amount *big.Int) {
+// Transfer subtracts amount from from's balance and adds it to to's balance.
+func Transfer(from, to vm.Account, amount *big.Int) {
 	core.Transfer(from, to, amount)
 }
-
-func (self *Env) Call(caller vm.ContractRef, addr common.Address, data []byte, nrg, price, value *big.Int) ([]byte, error) {
-	return core.Call(self, caller, addr, data, nrg, price, value)
-}
-func (self *Env) CallCode(caller vm.ContractRef, addr common.Address, data []byte, nrg, price, value *big.Int) ([]byte, error) {
-	return core.CallCode(self, caller, addr, data, nrg, price, value)
-}
-
-func (self *Env) DelegateCall(me vm.ContractRef, addr common.Address, data []byte, nrg, price *big.Int) ([]byte, error) {
-	return core.DelegateCall(self, me, addr, data, nrg, price)
-}
-
-func (self *Env) Create(caller vm.ContractRef, data []byte, nrg, price, value *big.Int) ([]byte, common.Address, error) {
-	return core.Create(self, caller, data, nrg, price, value)
-}
@@ -0,0 +1,110 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package runtime provides a basic execution model for running EVM code
+// without requiring a full blockchain, block or transaction context. It is
+// primarily intended for fuzzing, benchmarking, and self-contained tests of
+// EVM semantics.
+package runtime
+
+import (
+	"math/big"
+
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/core/state"
+	"github.com/shiftcurrency/shift/core/vm"
+)
+
+// Execute executes the code using the input as call data during execution.
+// It returns the EVM's return value, the resulting state and the amount of
+// nrg left, or an error if it failed.
+//
+// Execute sets up an in-memory, temporary environment for the execution of
+// the given code, auto-populating any Config fields that were left nil with
+// sensible defaults.
+func Execute(code, input []byte, value *big.Int, cfg *Config) ([]byte, *state.StateDB, uint64, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	setDefaults(cfg)
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	var (
+		address = common.StringToAddress("contract")
+		vmenv   = NewEnv(cfg, cfg.State)
+		sender  = vm.AccountRef(cfg.Origin)
+	)
+	cfg.State.CreateAccount(address)
+	cfg.State.SetCode(address, code)
+
+	ret, nrgLeft, err := vmenv.Call(sender, address, input, cfg.NrgLimit, new(big.Int), value)
+
+	return ret, cfg.State, nrgLeft, err
+}
+
+// Create executes the code using the EVM create method. It returns the
+// returned runtime bytecode, the address the code was deployed to, the
+// resulting state, the amount of nrg left and an error if the deployment
+// failed.
+func Create(input []byte, value *big.Int, cfg *Config) ([]byte, common.Address, *state.StateDB, uint64, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	setDefaults(cfg)
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	var (
+		vmenv  = NewEnv(cfg, cfg.State)
+		sender = vm.AccountRef(cfg.Origin)
+	)
+	cfg.State.CreateAccount(cfg.Origin)
+
+	ret, address, nrgLeft, err := vmenv.Create(sender, input, cfg.NrgLimit, new(big.Int), value)
+
+	return ret, address, cfg.State, nrgLeft, err
+}
+
+// Call executes the code given by the contract's address. It returns the
+// EVM's return value, the resulting state, the amount of nrg left and an
+// error if it failed.
+//
+// Call, unlike Execute, requires cfg.State to already hold the contract's
+// deployed code.
+func Call(address common.Address, input []byte, value *big.Int, cfg *Config) ([]byte, *state.StateDB, uint64, error) {
+	if cfg == nil {
+		cfg = new(Config)
+	}
+	setDefaults(cfg)
+
+	if value == nil {
+		value = new(big.Int)
+	}
+
+	var (
+		vmenv  = NewEnv(cfg, cfg.State)
+		sender = vm.AccountRef(cfg.Origin)
+	)
+
+	ret, nrgLeft, err := vmenv.Call(sender, address, input, cfg.NrgLimit, new(big.Int), value)
+
+	return ret, cfg.State, nrgLeft, err
+}
@@ -0,0 +1,132 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package runtime
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/shiftcurrency/shift/common"
+)
+
+// store32 is PUSH1 0x2a PUSH1 0x00 MSTORE PUSH1 0x20 PUSH1 0x00 RETURN: it
+// stores 0x2a at memory offset 0 and returns the 32-byte word.
+var store32 = []byte{
+	0x60, 0x2a, // PUSH1 0x2a
+	0x60, 0x00, // PUSH1 0x00
+	0x52,       // MSTORE
+	0x60, 0x20, // PUSH1 0x20
+	0x60, 0x00, // PUSH1 0x00
+	0xf3, // RETURN
+}
+
+func TestExecute(t *testing.T) {
+	ret, _, nrgLeft, err := Execute(store32, nil, nil, nil)
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if len(ret) != 32 || ret[31] != 0x2a {
+		t.Fatalf("unexpected return value: %x", ret)
+	}
+	if nrgLeft == 0 {
+		t.Errorf("expected some nrg left over after running a handful of opcodes")
+	}
+}
+
+func TestExecuteNrgLeftReflectsConsumption(t *testing.T) {
+	cfg := &Config{NrgLimit: 1000000}
+	_, _, nrgLeft, err := Execute(store32, nil, nil, cfg)
+	if err != nil {
+		t.Fatalf("execution failed: %v", err)
+	}
+	if nrgLeft == cfg.NrgLimit {
+		t.Fatalf("nrgLeft (%d) should reflect actual consumption, not echo the input NrgLimit (%d)", nrgLeft, cfg.NrgLimit)
+	}
+	if nrgLeft >= cfg.NrgLimit {
+		t.Errorf("nrgLeft (%d) must be less than the NrgLimit (%d)", nrgLeft, cfg.NrgLimit)
+	}
+}
+
+func TestCreate(t *testing.T) {
+	// A constructor that deploys store32 verbatim as the contract's runtime
+	// code: PUSH10 <store32> PUSH1 0x0a PUSH1 0x01 PUSH1 0x00 CODECOPY
+	// PUSH1 0x0a PUSH1 0x00 RETURN. CODECOPY copies the 10 bytes of store32,
+	// which start at code offset 1, to memory offset 0.
+	code := append([]byte{
+		0x69, // PUSH10
+	}, store32...)
+	code = append(code, []byte{
+		0x60, 0x0a, // PUSH1 0x0a
+		0x60, 0x01, // PUSH1 0x01
+		0x60, 0x00, // PUSH1 0x00
+		0x39,       // CODECOPY
+		0x60, 0x0a, // PUSH1 0x0a
+		0x60, 0x00, // PUSH1 0x00
+		0xf3, // RETURN
+	}...)
+
+	ret, address, _, err := Create(code, nil, nil)
+	if err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if len(ret) != len(store32) {
+		t.Fatalf("unexpected deployed code length: got %d, want %d", len(ret), len(store32))
+	}
+	if (address == common.Address{}) {
+		t.Errorf("expected a non-zero contract address")
+	}
+}
+
+func TestCall(t *testing.T) {
+	cfg := new(Config)
+	setDefaults(cfg)
+
+	address := common.StringToAddress("contract")
+	cfg.State.CreateAccount(address)
+	cfg.State.SetCode(address, store32)
+
+	ret, _, nrgLeft, err := Call(address, nil, nil, cfg)
+	if err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if len(ret) != 32 || ret[31] != 0x2a {
+		t.Fatalf("unexpected return value: %x", ret)
+	}
+	if nrgLeft == 0 {
+		t.Errorf("expected some nrg left over after running a handful of opcodes")
+	}
+}
+
+func TestCallValueTransfer(t *testing.T) {
+	cfg := new(Config)
+	setDefaults(cfg)
+
+	address := common.StringToAddress("contract")
+	cfg.State.CreateAccount(address)
+	cfg.State.SetCode(address, store32)
+	cfg.State.AddBalance(cfg.Origin, big.NewInt(100))
+
+	if _, _, _, err := Call(address, nil, big.NewInt(100), cfg); err != nil {
+		t.Fatalf("call failed: %v", err)
+	}
+	if got := cfg.State.GetBalance(address); got.Cmp(big.NewInt(100)) != 0 {
+		t.Errorf("contract balance = %v, want 100", got)
+	}
+	if got := cfg.State.GetBalance(cfg.Origin); got.Sign() != 0 {
+		t.Errorf("origin balance = %v, want 0", got)
+	}
+}
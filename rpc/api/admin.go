@@ -0,0 +1,235 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"io"
+	"os"
+
+	"github.com/shiftcurrency/shift/core/types"
+	"github.com/shiftcurrency/shift/logger/glog"
+	"github.com/shiftcurrency/shift/p2p/discover"
+	"github.com/shiftcurrency/shift/rlp"
+	"github.com/shiftcurrency/shift/rpc/codec"
+	"github.com/shiftcurrency/shift/rpc/shared"
+	"github.com/shiftcurrency/shift/shf"
+)
+
+const (
+	AdminApiVersion = "1.0"
+
+	importBatchSize = 2500
+)
+
+// admin api provider
+type adminApi struct {
+	shift   *shf.Shift
+	methods map[string]adminhandler
+	codec   codec.ApiCoder
+}
+
+// admin callback handler
+type adminhandler func(*adminApi, *shared.Request) (interface{}, error)
+
+var (
+	adminMapping = map[string]adminhandler{
+		"admin_addPeer":     (*adminApi).AddPeer,
+		"admin_peers":       (*adminApi).Peers,
+		"admin_nodeInfo":    (*adminApi).NodeInfo,
+		"admin_exportChain": (*adminApi).ExportChain,
+		"admin_importChain": (*adminApi).ImportChain,
+		"admin_verbosity":   (*adminApi).Verbosity,
+		"admin_syncStatus":  (*adminApi).SyncStatus,
+		"admin_setSolc":     (*adminApi).SetSolc,
+	}
+)
+
+// create new adminApi instance
+func NewAdminApi(shift *shf.Shift, codec codec.Codec) *adminApi {
+	return &adminApi{shift, adminMapping, codec.New(nil)}
+}
+
+// collection with supported methods
+func (self *adminApi) Methods() []string {
+	methods := make([]string, len(self.methods))
+	i := 0
+	for k := range self.methods {
+		methods[i] = k
+		i++
+	}
+	return methods
+}
+
+// Execute given request
+func (self *adminApi) Execute(req *shared.Request) (interface{}, error) {
+	if callback, ok := self.methods[req.Method]; ok {
+		return callback(self, req)
+	}
+
+	return nil, shared.NewNotImplementedError(req.Method)
+}
+
+func (self *adminApi) Name() string {
+	return shared.AdminApiName
+}
+
+func (self *adminApi) ApiVersion() string {
+	return AdminApiVersion
+}
+
+func (self *adminApi) AddPeer(req *shared.Request) (interface{}, error) {
+	args := new(AddPeerArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	node, err := discoverNodeFromString(args.Url)
+	if err != nil {
+		return nil, err
+	}
+	self.shift.Server().AddPeer(node)
+	return true, nil
+}
+
+func (self *adminApi) Peers(req *shared.Request) (interface{}, error) {
+	return self.shift.Server().PeersInfo(), nil
+}
+
+func (self *adminApi) NodeInfo(req *shared.Request) (interface{}, error) {
+	return self.shift.Server().NodeInfo(), nil
+}
+
+func (self *adminApi) ExportChain(req *shared.Request) (interface{}, error) {
+	args := new(FileArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	fh, err := os.OpenFile(args.Path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return false, err
+	}
+	defer fh.Close()
+
+	bc := self.shift.BlockChain()
+	for number := uint64(1); number <= bc.CurrentBlock().NumberU64(); number += importBatchSize {
+		blocks := make([]interface{}, 0, importBatchSize)
+		for i := number; i < number+importBatchSize && i <= bc.CurrentBlock().NumberU64(); i++ {
+			if block := bc.GetBlockByNumber(i); block != nil {
+				blocks = append(blocks, block)
+			}
+		}
+		if err := rlp.Encode(fh, blocks); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (self *adminApi) ImportChain(req *shared.Request) (interface{}, error) {
+	args := new(FileArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	fh, err := os.Open(args.Path)
+	if err != nil {
+		return false, err
+	}
+	defer fh.Close()
+
+	stream := rlp.NewStream(fh, 0)
+	bc := self.shift.BlockChain()
+	for {
+		var blocks types.Blocks
+		if err := stream.Decode(&blocks); err == io.EOF {
+			break
+		} else if err != nil {
+			return false, err
+		}
+
+		// admin_exportChain includes every block it can read starting from
+		// #1, and re-importing a file exported while the chain already held
+		// some of those blocks (or the genesis, which is never exported as
+		// of #1 above) must not re-feed them to InsertChain.
+		fresh := blocks[:0]
+		for _, block := range blocks {
+			if block.NumberU64() == 0 || bc.HasBlock(block.Hash()) {
+				continue
+			}
+			fresh = append(fresh, block)
+		}
+		if len(fresh) == 0 {
+			continue
+		}
+		if _, err := bc.InsertChain(fresh); err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+func (self *adminApi) Verbosity(req *shared.Request) (interface{}, error) {
+	args := new(VerbosityArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+	glog.SetV(args.Level)
+	return true, nil
+}
+
+func (self *adminApi) SyncStatus(req *shared.Request) (interface{}, error) {
+	origin, current, height := self.shift.Downloader().Progress()
+	return map[string]interface{}{
+		"startingBlock": origin,
+		"currentBlock":  current,
+		"highestBlock":  height,
+	}, nil
+}
+
+func (self *adminApi) SetSolc(req *shared.Request) (interface{}, error) {
+	args := new(FileArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	solc, err := self.shift.SetSolc(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	return solc.Info(), nil
+}
+
+func discoverNodeFromString(rawurl string) (*discover.Node, error) {
+	return discover.ParseNode(rawurl)
+}
+
+// AddPeerArgs are the arguments of admin_addPeer
+type AddPeerArgs struct {
+	Url string
+}
+
+// FileArgs are the arguments of the admin methods that take a single
+// filesystem path (admin_exportChain, admin_importChain, admin_setSolc)
+type FileArgs struct {
+	Path string
+}
+
+// VerbosityArgs are the arguments of admin_verbosity
+type VerbosityArgs struct {
+	Level int
+}
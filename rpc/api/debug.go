@@ -0,0 +1,238 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/core"
+	"github.com/shiftcurrency/shift/core/vm"
+	"github.com/shiftcurrency/shift/rlp"
+	"github.com/shiftcurrency/shift/rpc/codec"
+	"github.com/shiftcurrency/shift/rpc/shared"
+	"github.com/shiftcurrency/shift/shf"
+	"github.com/shiftcurrency/shift/xeth"
+)
+
+const DebugApiVersion = "1.0"
+
+// debug api provider
+type debugApi struct {
+	xeth    *xeth.XEth
+	shift   *shf.Shift
+	methods map[string]debughandler
+	codec   codec.ApiCoder
+}
+
+// debug callback handler
+type debughandler func(*debugApi, *shared.Request) (interface{}, error)
+
+var (
+	debugMapping = map[string]debughandler{
+		"debug_getBlockRlp":      (*debugApi).GetBlockRlp,
+		"debug_printBlock":       (*debugApi).PrintBlock,
+		"debug_setHead":          (*debugApi).SetHead,
+		"debug_dumpBlock":        (*debugApi).DumpBlock,
+		"debug_traceTransaction": (*debugApi).TraceTransaction,
+	}
+)
+
+// create new debugApi instance
+func NewDebugApi(xeth *xeth.XEth, shift *shf.Shift, codec codec.Codec) *debugApi {
+	return &debugApi{xeth, shift, debugMapping, codec.New(nil)}
+}
+
+// collection with supported methods
+func (self *debugApi) Methods() []string {
+	methods := make([]string, len(self.methods))
+	i := 0
+	for k := range self.methods {
+		methods[i] = k
+		i++
+	}
+	return methods
+}
+
+// Execute given request
+func (self *debugApi) Execute(req *shared.Request) (interface{}, error) {
+	if callback, ok := self.methods[req.Method]; ok {
+		return callback(self, req)
+	}
+
+	return nil, shared.NewNotImplementedError(req.Method)
+}
+
+func (self *debugApi) Name() string {
+	return shared.DebugApiName
+}
+
+func (self *debugApi) ApiVersion() string {
+	return DebugApiVersion
+}
+
+func (self *debugApi) GetBlockRlp(req *shared.Request) (interface{}, error) {
+	args := new(BlockNumArg)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	block := self.xeth.EthBlockByNumber(args.BlockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", args.BlockNumber)
+	}
+	encoded, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		return nil, err
+	}
+	return fmt.Sprintf("%x", encoded), nil
+}
+
+func (self *debugApi) PrintBlock(req *shared.Request) (interface{}, error) {
+	args := new(BlockNumArg)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	block := self.xeth.EthBlockByNumber(args.BlockNumber)
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", args.BlockNumber)
+	}
+	return fmt.Sprintf("%v", block), nil
+}
+
+func (self *debugApi) SetHead(req *shared.Request) (interface{}, error) {
+	args := new(BlockNumArg)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	self.shift.BlockChain().SetHead(uint64(args.BlockNumber))
+	return true, nil
+}
+
+func (self *debugApi) DumpBlock(req *shared.Request) (interface{}, error) {
+	args := new(BlockNumArg)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	state := self.xeth.AtStateNum(args.BlockNumber).State()
+	if state == nil {
+		return nil, fmt.Errorf("block #%d not found", args.BlockNumber)
+	}
+	return state.RawDump(), nil
+}
+
+// TraceOptions controls which parts of a StructLog are populated. Heavy
+// fields are opt-out: by default stack, memory and storage are all recorded,
+// and each can be disabled to keep traces over long running transactions
+// bounded.
+type TraceOptions struct {
+	DisableStack   bool
+	DisableMemory  bool
+	DisableStorage bool
+}
+
+// TraceTransactionArgs are the arguments of debug_traceTransaction
+type TraceTransactionArgs struct {
+	Hash    string
+	Options *TraceOptions
+}
+
+func (self *debugApi) TraceTransaction(req *shared.Request) (interface{}, error) {
+	args := new(TraceTransactionArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	tx, bhash, _, txi := self.xeth.EthTransactionByHash(args.Hash)
+	if tx == nil {
+		return nil, fmt.Errorf("transaction %s not found", args.Hash)
+	}
+	block := self.xeth.EthBlockByHash(bhash.Hex())
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", bhash.Hex())
+	}
+
+	// Reconstruct the state at the start of the block, then replay every
+	// preceding transaction to arrive at the target tx's pre-state.
+	statedb := self.xeth.AtStateNum(block.NumberU64() - 1).State()
+	if statedb == nil {
+		return nil, fmt.Errorf("pre-state for block #%d not available", block.NumberU64())
+	}
+	statedb = statedb.Copy()
+	getHash := blockHashFn(self.shift.BlockChain())
+
+	var logs []vm.StructLog
+	for i, txn := range block.Transactions() {
+		from, _ := txn.FromFrontier()
+		context := vm.Context{
+			Origin:      from,
+			Coinbase:    block.Coinbase(),
+			BlockNumber: block.Number(),
+			Time:        new(big.Int).SetUint64(block.Time()),
+			Difficulty:  block.Difficulty(),
+			NrgLimit:    block.NrgLimit().Uint64(),
+			GetHash:     getHash,
+			CanTransfer: traceCanTransfer,
+			Transfer:    traceTransfer,
+		}
+		evm := vm.NewEVM(context, statedb)
+
+		tracing := int64(i) == txi
+		if tracing && args.Options != nil {
+			evm.SetOptions(!args.Options.DisableStack, !args.Options.DisableMemory, !args.Options.DisableStorage)
+		}
+
+		if to := txn.To(); to != nil {
+			evm.Call(vm.AccountRef(from), *to, txn.Data(), txn.Gas().Uint64(), txn.GasPrice(), txn.Value())
+		} else {
+			evm.Create(vm.AccountRef(from), txn.Data(), txn.Gas().Uint64(), txn.GasPrice(), txn.Value())
+		}
+		if tracing {
+			logs = evm.StructLogs()
+			break
+		}
+	}
+
+	return logs, nil
+}
+
+// traceCanTransfer and traceTransfer mirror runtime.CanTransfer/runtime.Transfer
+// so that replaying a block's transactions for debug_traceTransaction moves
+// real balances, the same as the live state transition does.
+func traceCanTransfer(db vm.Database, addr common.Address, amount *big.Int) bool {
+	return db.GetBalance(addr).Cmp(amount) >= 0
+}
+
+func traceTransfer(from, to vm.Account, amount *big.Int) {
+	core.Transfer(from, to, amount)
+}
+
+// blockHashFn returns a vm.GetHashFunc that resolves BLOCKHASH lookups
+// against the real chain instead of always returning the zero hash.
+func blockHashFn(bc *core.BlockChain) func(n uint64) common.Hash {
+	return func(n uint64) common.Hash {
+		header := bc.GetHeaderByNumber(n)
+		if header == nil {
+			return common.Hash{}
+		}
+		return header.Hash()
+	}
+}
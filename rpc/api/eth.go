@@ -25,10 +25,10 @@ import (
 
 	"github.com/shiftcurrency/shift/common"
 	"github.com/shiftcurrency/shift/common/natspec"
-	"github.com/shiftcurrency/shift/shf"
 	"github.com/shiftcurrency/shift/rlp"
 	"github.com/shiftcurrency/shift/rpc/codec"
 	"github.com/shiftcurrency/shift/rpc/shared"
+	"github.com/shiftcurrency/shift/shf"
 	"github.com/shiftcurrency/shift/xeth"
 	"gopkg.in/fatih/set.v0"
 )
@@ -40,10 +40,11 @@ const (
 // shf api provider
 // See https://github.com/shiftcurrency/wiki/wiki/JSON-RPC
 type ethApi struct {
-	xeth     *xeth.XEth
-	shift *shf.Shift
-	methods  map[string]ethhandler
-	codec    codec.ApiCoder
+	xeth    *xeth.XEth
+	shift   *shf.Shift
+	methods map[string]ethhandler
+	codec   codec.ApiCoder
+	subs    *subscriptionManager
 }
 
 // shf callback handler
@@ -55,7 +56,7 @@ var (
 		"shf_blockNumber":                         (*ethApi).BlockNumber,
 		"shf_getBalance":                          (*ethApi).GetBalance,
 		"shf_protocolVersion":                     (*ethApi).ProtocolVersion,
-		"shf_shiftbase":                            (*ethApi).Coinbase,
+		"shf_shiftbase":                           (*ethApi).Coinbase,
 		"shf_mining":                              (*ethApi).IsMining,
 		"shf_syncing":                             (*ethApi).IsSyncing,
 		"shf_nrgPrice":                            (*ethApi).NrgPrice,
@@ -99,12 +100,15 @@ var (
 		"shf_resend":                              (*ethApi).Resend,
 		"shf_pendingTransactions":                 (*ethApi).PendingTransactions,
 		"shf_getTransactionReceipt":               (*ethApi).GetTransactionReceipt,
+		"shf_subscribe":                           (*ethApi).Subscribe,
+		"shf_unsubscribe":                         (*ethApi).Unsubscribe,
+		"shf_getProof":                            (*ethApi).GetProof,
 	}
 )
 
 // create new ethApi instance
 func NewEthApi(xeth *xeth.XEth, shf *shf.Shift, codec codec.Codec) *ethApi {
-	return &ethApi{xeth, shf, ethMapping, codec.New(nil)}
+	return &ethApi{xeth, shf, ethMapping, codec.New(nil), newSubscriptionManager()}
 }
 
 // collection with supported methods
@@ -135,6 +139,12 @@ func (self *ethApi) ApiVersion() string {
 	return EthApiVersion
 }
 
+// Close tears down any live shf_subscribe streams. It must be called by the
+// transport when the underlying connection closes.
+func (self *ethApi) Close() {
+	self.subs.closeAll()
+}
+
 func (self *ethApi) Accounts(req *shared.Request) (interface{}, error) {
 	return self.xeth.Accounts(), nil
 }
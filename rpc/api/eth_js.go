@@ -0,0 +1,43 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+// Eth_JS binds the shf namespace's extensions beyond what web3.js already
+// provides for the standard eth_* methods: subscriptions and shf_getProof.
+const Eth_JS = `
+web3._extend({
+	property: 'shf',
+	methods:
+	[
+		new web3._extend.Method({
+			name: 'subscribe',
+			call: 'shf_subscribe',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'unsubscribe',
+			call: 'shf_unsubscribe',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getProof',
+			call: 'shf_getProof',
+			params: 3
+		})
+	]
+});
+`
@@ -0,0 +1,272 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/shiftcurrency/shift/accounts"
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/rpc/codec"
+	"github.com/shiftcurrency/shift/rpc/shared"
+	"github.com/shiftcurrency/shift/shf"
+	"github.com/shiftcurrency/shift/xeth"
+)
+
+const (
+	PersonalApiVersion = "1.0"
+
+	// defaultUnlockDuration is used when personal_unlockAccount is called
+	// without a duration, and as the timeout of the temporary unlock that
+	// personal_sendTransaction makes for a locked account. A duration of zero
+	// means "unlock for a single transaction" and does not use it.
+	defaultUnlockDuration = 300 * time.Second
+)
+
+// unlockState is this api's own record of an account it unlocked, kept so it
+// can tell whether an account is still unlocked without guessing from a
+// failed signing attempt. expiry is the zero Time for an indefinite unlock.
+type unlockState struct {
+	oneShot bool
+	expiry  time.Time
+}
+
+func (u unlockState) active() bool {
+	return u.expiry.IsZero() || time.Now().Before(u.expiry)
+}
+
+// personal api provider
+type personalApi struct {
+	xeth    *xeth.XEth
+	shift   *shf.Shift
+	methods map[string]personalhandler
+	codec   codec.ApiCoder
+
+	mu       sync.Mutex
+	unlocked map[common.Address]unlockState
+}
+
+// personal callback handler
+type personalhandler func(*personalApi, *shared.Request) (interface{}, error)
+
+var (
+	personalMapping = map[string]personalhandler{
+		"personal_listAccounts":    (*personalApi).ListAccounts,
+		"personal_newAccount":      (*personalApi).NewAccount,
+		"personal_unlockAccount":   (*personalApi).UnlockAccount,
+		"personal_lockAccount":     (*personalApi).LockAccount,
+		"personal_sendTransaction": (*personalApi).SendTransaction,
+	}
+)
+
+// create new personalApi instance
+func NewPersonalApi(xeth *xeth.XEth, shift *shf.Shift, codec codec.Codec) *personalApi {
+	return &personalApi{xeth, shift, personalMapping, codec.New(nil), sync.Mutex{}, make(map[common.Address]unlockState)}
+}
+
+// collection with supported methods
+func (self *personalApi) Methods() []string {
+	methods := make([]string, len(self.methods))
+	i := 0
+	for k := range self.methods {
+		methods[i] = k
+		i++
+	}
+	return methods
+}
+
+// Execute given request
+func (self *personalApi) Execute(req *shared.Request) (interface{}, error) {
+	if callback, ok := self.methods[req.Method]; ok {
+		return callback(self, req)
+	}
+
+	return nil, shared.NewNotImplementedError(req.Method)
+}
+
+func (self *personalApi) Name() string {
+	return shared.PersonalApiName
+}
+
+func (self *personalApi) ApiVersion() string {
+	return PersonalApiVersion
+}
+
+func (self *personalApi) ListAccounts(req *shared.Request) (interface{}, error) {
+	return self.xeth.Accounts(), nil
+}
+
+func (self *personalApi) NewAccount(req *shared.Request) (interface{}, error) {
+	args := new(NewAccountArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	acc, err := self.shift.AccountManager().NewAccount(args.Passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return acc.Address.Hex(), nil
+}
+
+func (self *personalApi) UnlockAccount(req *shared.Request) (interface{}, error) {
+	args := new(UnlockAccountArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	// args.Duration is a pointer so that an explicit 0 ("unlock for a single
+	// transaction") can be told apart from an omitted duration, which falls
+	// back to the default. A duration of 0 is passed to TimedUnlock as an
+	// indefinite unlock, but the address is flagged one-shot in our own
+	// bookkeeping so the next personal_sendTransaction locks it again once
+	// that transaction is sent.
+	oneShot := args.Duration != nil && *args.Duration == 0
+	duration := defaultUnlockDuration
+	switch {
+	case oneShot:
+		duration = 0
+	case args.Duration != nil:
+		duration = time.Duration(*args.Duration) * time.Second
+	}
+
+	address := common.HexToAddress(args.Address)
+	account := accounts.Account{Address: address}
+	if err := self.shift.AccountManager().TimedUnlock(account, args.Passphrase, duration); err != nil {
+		return false, err
+	}
+	self.markUnlocked(address, oneShot, duration)
+	return true, nil
+}
+
+func (self *personalApi) LockAccount(req *shared.Request) (interface{}, error) {
+	args := new(HashArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	address := common.HexToAddress(args.Hash)
+	ok := self.shift.AccountManager().Lock(address) == nil
+	self.markLocked(address)
+	return ok, nil
+}
+
+// SendTransaction signs and submits a transaction. If the sending account is
+// already unlocked (per our own bookkeeping, not a guess from a failed
+// Transact), it's used as-is and stays unlocked, unless it was unlocked for a
+// single transaction (duration 0), in which case it's locked again once the
+// transaction is sent successfully; otherwise it's unlocked
+// with the given passphrase for this call only and locked again immediately
+// afterwards, so a longer-lived unlock from personal_unlockAccount is never
+// cut short by an unrelated personal_sendTransaction call.
+func (self *personalApi) SendTransaction(req *shared.Request) (interface{}, error) {
+	args := new(SendTxArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	from := common.HexToAddress(args.From)
+	account := accounts.Account{Address: from}
+
+	var nonce, nrg, price, value string
+	if args.Tx.Nonce != nil {
+		nonce = args.Tx.Nonce.String()
+	}
+	if args.Tx.Nrg != nil {
+		nrg = args.Tx.Nrg.String()
+	}
+	if args.Tx.NrgPrice != nil {
+		price = args.Tx.NrgPrice.String()
+	}
+	if args.Tx.Value != nil {
+		value = args.Tx.Value.String()
+	}
+
+	if !self.isUnlocked(from) {
+		if err := self.shift.AccountManager().TimedUnlock(account, args.Passphrase, defaultUnlockDuration); err != nil {
+			return nil, err
+		}
+		self.markUnlocked(from, false, defaultUnlockDuration)
+		defer func() {
+			self.shift.AccountManager().Lock(from)
+			self.markLocked(from)
+		}()
+	}
+
+	res, err := self.xeth.Transact(args.From, args.Tx.To, nonce, value, nrg, price, args.Tx.Data)
+	if err == nil {
+		self.mu.Lock()
+		if u, ok := self.unlocked[from]; ok && u.oneShot {
+			delete(self.unlocked, from)
+			self.mu.Unlock()
+			self.shift.AccountManager().Lock(from)
+		} else {
+			self.mu.Unlock()
+		}
+	}
+	return res, err
+}
+
+// isUnlocked reports whether address is currently unlocked per this api's
+// own bookkeeping, pruning the record first if a timed unlock has expired.
+func (self *personalApi) isUnlocked(address common.Address) bool {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	u, ok := self.unlocked[address]
+	if ok && !u.active() {
+		delete(self.unlocked, address)
+		return false
+	}
+	return ok
+}
+
+func (self *personalApi) markUnlocked(address common.Address, oneShot bool, duration time.Duration) {
+	state := unlockState{oneShot: oneShot}
+	if duration > 0 {
+		state.expiry = time.Now().Add(duration)
+	}
+	self.mu.Lock()
+	self.unlocked[address] = state
+	self.mu.Unlock()
+}
+
+func (self *personalApi) markLocked(address common.Address) {
+	self.mu.Lock()
+	delete(self.unlocked, address)
+	self.mu.Unlock()
+}
+
+// NewAccountArgs are the arguments of personal_newAccount
+type NewAccountArgs struct {
+	Passphrase string
+}
+
+// UnlockAccountArgs are the arguments of personal_unlockAccount. Duration is
+// a pointer so an explicit 0 can be told apart from an omitted argument.
+type UnlockAccountArgs struct {
+	Address    string
+	Passphrase string
+	Duration   *int64
+}
+
+// SendTxArgs are the arguments of personal_sendTransaction
+type SendTxArgs struct {
+	From       string
+	Tx         NewTxArgs
+	Passphrase string
+}
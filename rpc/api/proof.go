@@ -0,0 +1,146 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"fmt"
+
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/crypto"
+	"github.com/shiftcurrency/shift/ethdb"
+	"github.com/shiftcurrency/shift/rpc/shared"
+)
+
+// GetProofArgs are the arguments of shf_getProof
+type GetProofArgs struct {
+	Address     string
+	StorageKeys []string
+	BlockNumber int64
+}
+
+// StorageProofRes is a single {key, value, proof} tuple of an
+// shf_getProof response.
+type StorageProofRes struct {
+	Key   string   `json:"key"`
+	Value string   `json:"value"`
+	Proof []string `json:"proof"`
+}
+
+// ProofRes is the response of shf_getProof: an EIP-1186-style Merkle proof
+// of an account and any of its requested storage slots.
+type ProofRes struct {
+	Address      string            `json:"address"`
+	Balance      string            `json:"balance"`
+	Nonce        string            `json:"nonce"`
+	CodeHash     string            `json:"codeHash"`
+	StorageHash  string            `json:"storageHash"`
+	AccountProof []string          `json:"accountProof"`
+	StorageProof []StorageProofRes `json:"storageProof"`
+}
+
+// GetProof returns the account and, for each requested storage key, the
+// Merkle proof of its presence (or absence) in the state trie at the given
+// block, so that a light client can verify the result against a header
+// without trusting this endpoint.
+func (self *ethApi) GetProof(req *shared.Request) (interface{}, error) {
+	args := new(GetProofArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	state := self.xeth.AtStateNum(args.BlockNumber).State()
+	if state == nil {
+		return nil, fmt.Errorf("state for block #%d not available", args.BlockNumber)
+	}
+
+	address := common.HexToAddress(args.Address)
+	accountProof, err := proveTrie(state.Trie(), crypto.Keccak256(address[:]))
+	if err != nil {
+		return nil, err
+	}
+
+	// Look up the account read-only: shf_getProof must not create an empty
+	// state object (and thus a bogus storage root) as a side effect of
+	// proving that an account doesn't exist.
+	account := state.GetStateObject(address)
+
+	// storageHash must reflect the account's real storage root even if no
+	// storage keys were requested (EIP-1186), so it's set once here rather
+	// than inside the per-key loop below.
+	storageHash := common.Hash{}
+	if account != nil {
+		storageHash = account.Root()
+	}
+
+	storageProof := make([]StorageProofRes, len(args.StorageKeys))
+	for i, k := range args.StorageKeys {
+		key := common.HexToHash(k)
+		var proof []string
+		if account != nil {
+			proof, err = proveTrie(account.Trie(), crypto.Keccak256(common.LeftPadBytes(key[:], 32)))
+			if err != nil {
+				return nil, err
+			}
+		}
+		storageProof[i] = StorageProofRes{
+			Key:   k,
+			Value: state.GetState(address, key).Hex(),
+			Proof: proof,
+		}
+	}
+
+	return ProofRes{
+		Address:      address.Hex(),
+		Balance:      state.GetBalance(address).String(),
+		Nonce:        fmt.Sprintf("%#x", state.GetNonce(address)),
+		CodeHash:     common.BytesToHash(state.GetCodeHash(address)).Hex(),
+		StorageHash:  storageHash.Hex(),
+		AccountProof: accountProof,
+		StorageProof: storageProof,
+	}, nil
+}
+
+// proveTrie walks t from its root down to key, recording the RLP of every
+// visited node, and returns them hex-encoded from root to leaf (or to the
+// nearest existing node, for an exclusion proof).
+func proveTrie(t proofTrie, key []byte) ([]string, error) {
+	var proof proofList
+	if err := t.Prove(key, 0, &proof); err != nil {
+		return nil, err
+	}
+
+	nodes := make([]string, len(proof))
+	for i, v := range proof {
+		nodes[i] = common.Bytes2Hex(v)
+	}
+	return nodes, nil
+}
+
+// proofList is an ethdb.Putter that keeps the nodes Prove hands it in the
+// order they are visited, i.e. from the root down.
+type proofList [][]byte
+
+func (l *proofList) Put(key []byte, value []byte) error {
+	*l = append(*l, append([]byte(nil), value...))
+	return nil
+}
+
+// proofTrie is satisfied by trie.SecureTrie; declared locally so this file
+// doesn't have to import the trie package just for the one method it uses.
+type proofTrie interface {
+	Prove(key []byte, fromLevel int, proofDb ethdb.Putter) error
+}
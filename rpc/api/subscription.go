@@ -0,0 +1,269 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+
+	"github.com/shiftcurrency/shift/common"
+	"github.com/shiftcurrency/shift/core"
+	"github.com/shiftcurrency/shift/core/state"
+	"github.com/shiftcurrency/shift/rpc/codec"
+	"github.com/shiftcurrency/shift/rpc/shared"
+)
+
+const (
+	subscriptionNewHeads               = "newHeads"
+	subscriptionLogs                   = "logs"
+	subscriptionNewPendingTransactions = "newPendingTransactions"
+	subscriptionSyncing                = "syncing"
+)
+
+// notifier is implemented by codecs whose transport can push unsolicited
+// messages to the client (IPC, WS). HTTP codecs can't, so shf_subscribe is
+// rejected on them instead of silently doing nothing.
+type notifier interface {
+	Notify(n *codec.Notification) error
+}
+
+// subscriptionManager tracks the live shf_subscribe streams for a single
+// connection and tears them all down when that connection goes away.
+type subscriptionManager struct {
+	mu   sync.Mutex
+	subs map[string]func()
+}
+
+func newSubscriptionManager() *subscriptionManager {
+	return &subscriptionManager{subs: make(map[string]func())}
+}
+
+func (m *subscriptionManager) add(id string, cancel func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.subs[id] = cancel
+}
+
+func (m *subscriptionManager) remove(id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	cancel, ok := m.subs[id]
+	if ok {
+		delete(m.subs, id)
+		cancel()
+	}
+	return ok
+}
+
+// closeAll cancels every outstanding subscription, e.g. on transport
+// disconnect.
+func (m *subscriptionManager) closeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, cancel := range m.subs {
+		cancel()
+		delete(m.subs, id)
+	}
+}
+
+func newSubscriptionId() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(b[:]), nil
+}
+
+// SubscribeArgs are the arguments of shf_subscribe
+type SubscribeArgs struct {
+	Kind   string
+	Params BlockFilterArgs
+}
+
+// UnsubscribeArgs are the arguments of shf_unsubscribe
+type UnsubscribeArgs struct {
+	Id string
+}
+
+func (self *ethApi) Subscribe(req *shared.Request) (interface{}, error) {
+	pusher, ok := self.codec.(notifier)
+	if !ok {
+		return nil, fmt.Errorf("shf_subscribe requires a bidirectional transport (IPC, WS)")
+	}
+
+	args := new(SubscribeArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+
+	id, err := newSubscriptionId()
+	if err != nil {
+		return nil, err
+	}
+
+	var events []interface{}
+	switch args.Kind {
+	case subscriptionNewHeads:
+		events = []interface{}{core.ChainHeadEvent{}}
+	case subscriptionLogs:
+		events = []interface{}{core.ChainEvent{}}
+	case subscriptionNewPendingTransactions:
+		events = []interface{}{core.TxPreEvent{}}
+	case subscriptionSyncing:
+		events = []interface{}{core.ChainSyncEvent{}}
+	default:
+		return nil, fmt.Errorf("unknown subscription kind %q", args.Kind)
+	}
+
+	sub := self.shift.EventMux().Subscribe(events...)
+	quit := make(chan struct{})
+	self.subs.add(id, func() {
+		close(quit)
+		sub.Unsubscribe()
+	})
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-sub.Chan():
+				if !ok {
+					return
+				}
+				payload, ok := filterSubscriptionPayload(args.Kind, args.Params, event.Data)
+				if !ok {
+					continue
+				}
+				pusher.Notify(&codec.Notification{
+					Version: "2.0",
+					Method:  "shf_subscription",
+					Params: codec.NotificationParams{
+						Subscription: id,
+						Result:       payload,
+					},
+				})
+			case <-quit:
+				return
+			}
+		}
+	}()
+
+	return id, nil
+}
+
+func (self *ethApi) Unsubscribe(req *shared.Request) (interface{}, error) {
+	args := new(UnsubscribeArgs)
+	if err := self.codec.Decode(req.Params, &args); err != nil {
+		return nil, shared.NewDecodeParamError(err.Error())
+	}
+	return self.subs.remove(args.Id), nil
+}
+
+// filterSubscriptionPayload turns a raw mux event into the value pushed to
+// the client, applying the {address, topics, fromBlock} filter for "logs"
+// subscriptions. The second return value is false if the event should be
+// dropped (e.g. a log that doesn't match the filter).
+func filterSubscriptionPayload(kind string, params BlockFilterArgs, data interface{}) (interface{}, bool) {
+	switch kind {
+	case subscriptionNewHeads:
+		ev, ok := data.(core.ChainHeadEvent)
+		if !ok {
+			return nil, false
+		}
+		return NewBlockRes(ev.Block, nil, false), true
+	case subscriptionLogs:
+		ev, ok := data.(core.ChainEvent)
+		if !ok {
+			return nil, false
+		}
+		matched := matchLogs(ev.Logs, params)
+		if len(matched) == 0 {
+			return nil, false
+		}
+		return NewLogsRes(matched), true
+	case subscriptionNewPendingTransactions:
+		ev, ok := data.(core.TxPreEvent)
+		if !ok {
+			return nil, false
+		}
+		return ev.Tx.Hash().Hex(), true
+	case subscriptionSyncing:
+		ev, ok := data.(core.ChainSyncEvent)
+		if !ok {
+			return nil, false
+		}
+		return ev.Syncing, true
+	}
+	return nil, false
+}
+
+// matchLogs keeps the logs satisfying the {address, topics, fromBlock}
+// filter carried in a "logs" subscription's Params, applying the same rules
+// shf_getLogs uses so a subscriber only sees logs it actually asked for.
+func matchLogs(logs state.Logs, params BlockFilterArgs) state.Logs {
+	var out state.Logs
+	for _, log := range logs {
+		if params.Earliest > 0 && int64(log.BlockNumber) < params.Earliest {
+			continue
+		}
+		if !matchLogAddress(params.Address, log.Address) {
+			continue
+		}
+		if !matchLogTopics(params.Topics, log.Topics) {
+			continue
+		}
+		out = append(out, log)
+	}
+	return out
+}
+
+// matchLogAddress reports whether addr is one of the requested addresses,
+// or whether no address filter was requested at all.
+func matchLogAddress(want []string, addr common.Address) bool {
+	if len(want) == 0 {
+		return true
+	}
+	for _, w := range want {
+		if common.HexToAddress(w) == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// matchLogTopics reports whether got contains every topic requested in
+// want (AND semantics), or whether no topic filter was requested at all.
+func matchLogTopics(want []string, got []common.Hash) bool {
+	for _, w := range want {
+		if w == "" {
+			continue
+		}
+		found := false
+		topic := common.HexToHash(w)
+		for _, g := range got {
+			if g == topic {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+// Copyright 2015 The shift Authors
+// This file is part of the shift library.
+//
+// The shift library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The shift library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the shift library. If not, see <http://www.gnu.org/licenses/>.
+
+package api
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shiftcurrency/shift/rpc/codec"
+	"github.com/shiftcurrency/shift/rpc/shared"
+	"github.com/shiftcurrency/shift/shf"
+	"github.com/shiftcurrency/shift/xeth"
+)
+
+// ParseApiString parses a comma separated API namespace string (e.g.
+// "eth,admin,personal") into the matching api instances. Each api builds its
+// own coder from the given codec.
+func ParseApiString(apistr string, codec codec.Codec, xeth *xeth.XEth, shift *shf.Shift) ([]shared.EthereumApi, error) {
+	if len(strings.TrimSpace(apistr)) == 0 {
+		return nil, fmt.Errorf("empty api string")
+	}
+
+	names := strings.Split(apistr, ",")
+	apis := make([]shared.EthereumApi, len(names))
+	for i, name := range names {
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case shared.EthApiName:
+			apis[i] = NewEthApi(xeth, shift, codec)
+		case shared.AdminApiName:
+			apis[i] = NewAdminApi(shift, codec)
+		case shared.PersonalApiName:
+			apis[i] = NewPersonalApi(xeth, shift, codec)
+		case shared.DebugApiName:
+			apis[i] = NewDebugApi(xeth, shift, codec)
+		default:
+			return nil, fmt.Errorf("unknown api namespace %q", name)
+		}
+	}
+	return apis, nil
+}
+
+// Javascript returns the web3 console bindings for the given API namespace,
+// or the empty string if that namespace has none.
+func Javascript(name string) string {
+	switch name {
+	case shared.EthApiName:
+		return Eth_JS
+	case shared.AdminApiName:
+		return Admin_JS
+	case shared.PersonalApiName:
+		return Personal_JS
+	case shared.DebugApiName:
+		return Debug_JS
+	}
+	return ""
+}
+
+// closer is implemented by apis that hold resources (e.g. shf_subscribe
+// streams) which must be torn down when the connection that owns them
+// closes.
+type closer interface {
+	Close()
+}
+
+// Shutdown closes every api in apis that holds connection-scoped resources.
+// The transport is responsible for calling this once, when the connection
+// it dispatches these apis for goes away.
+func Shutdown(apis []shared.EthereumApi) {
+	for _, api := range apis {
+		if c, ok := api.(closer); ok {
+			c.Close()
+		}
+	}
+}